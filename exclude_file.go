@@ -0,0 +1,142 @@
+package codegen
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExcludeFile is the parsed contents of a .searchignore-style file: the
+// path patterns, directories, sentinel files, and string-literal exclusions
+// it declares. Load one with LoadExcludesFromFile; ExclusionList.ExcludeFiles
+// loads and merges the path-based fields automatically.
+type ExcludeFile struct {
+	FilePatterns     []string
+	Directories      []string
+	ExcludeIfPresent []string
+	ExcludeLiterals  []string
+}
+
+// LoadExcludesFromFile parses the simple text format used by .searchignore
+// files, mirroring restic's --exclude-file:
+//
+//	# blank lines and lines starting with "#" are ignored
+//	literal: loads data from
+//	pattern: *_test.go
+//	dir: services/codegen/testdata
+//	sentinel: CACHEDIR.TAG
+//	include: other.txt
+//
+// "dir:" values are resolved relative to path's own directory (after "~"
+// expansion) so the file can be moved or copied without rewriting it.
+// "include:" pulls in another file the same way, recursively, so a project
+// can split its excludes across several reviewable files. A "literal:"
+// value keeps its exact spacing -- only the single space conventionally
+// separating it from the colon is stripped -- since padding can be
+// semantically part of the literal (" using proc " excludes different
+// lines than "using proc" would).
+func LoadExcludesFromFile(path string) (ExcludeFile, error) {
+	return loadExcludesFromFile(path, map[string]bool{})
+}
+
+func loadExcludesFromFile(path string, visited map[string]bool) (ExcludeFile, error) {
+	path, err := resolvePath(path, "")
+	if err != nil {
+		return ExcludeFile{}, err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return ExcludeFile{}, fmt.Errorf("resolving %s: %w", path, err)
+	}
+	if visited[absPath] {
+		return ExcludeFile{}, fmt.Errorf("circular include of %s", path)
+	}
+	visited[absPath] = true
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ExcludeFile{}, fmt.Errorf("opening exclude file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	baseDir := filepath.Dir(absPath)
+	var ef ExcludeFile
+
+	scanner := bufio.NewScanner(f)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		raw := scanner.Text()
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		directive, value, ok := strings.Cut(strings.TrimLeft(raw, " \t"), ":")
+		if !ok {
+			return ExcludeFile{}, fmt.Errorf("%s:%d: expected \"directive: value\", got %q", path, lineNumber, trimmed)
+		}
+		directive = strings.TrimSpace(directive)
+
+		switch directive {
+		case "literal":
+			// Leading/trailing whitespace can be semantically part of a
+			// literal (" using proc " excludes different lines than "using
+			// proc" would), so only the single conventional separator space
+			// after the colon is stripped -- the rest of the value is kept
+			// exactly as written.
+			ef.ExcludeLiterals = append(ef.ExcludeLiterals, strings.TrimPrefix(value, " "))
+		case "pattern":
+			ef.FilePatterns = append(ef.FilePatterns, strings.TrimSpace(value))
+		case "sentinel":
+			ef.ExcludeIfPresent = append(ef.ExcludeIfPresent, strings.TrimSpace(value))
+		case "dir":
+			dir, err := resolvePath(strings.TrimSpace(value), baseDir)
+			if err != nil {
+				return ExcludeFile{}, fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+			}
+			ef.Directories = append(ef.Directories, dir)
+		case "include":
+			includePath, err := resolvePath(strings.TrimSpace(value), baseDir)
+			if err != nil {
+				return ExcludeFile{}, fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+			}
+			included, err := loadExcludesFromFile(includePath, visited)
+			if err != nil {
+				return ExcludeFile{}, fmt.Errorf("%s:%d: including %s: %w", path, lineNumber, strings.TrimSpace(value), err)
+			}
+			ef.FilePatterns = append(ef.FilePatterns, included.FilePatterns...)
+			ef.Directories = append(ef.Directories, included.Directories...)
+			ef.ExcludeIfPresent = append(ef.ExcludeIfPresent, included.ExcludeIfPresent...)
+			ef.ExcludeLiterals = append(ef.ExcludeLiterals, included.ExcludeLiterals...)
+		default:
+			return ExcludeFile{}, fmt.Errorf("%s:%d: unknown directive %q", path, lineNumber, directive)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ExcludeFile{}, fmt.Errorf("reading exclude file %s: %w", path, err)
+	}
+
+	return ef, nil
+}
+
+// resolvePath expands a leading "~" to the user's home directory, then, if
+// the result is still relative, resolves it against baseDir (the directory
+// an exclude file directive was read from). An empty baseDir leaves a
+// relative path as-is, resolved against the process's working directory by
+// whatever opens it next.
+func resolvePath(path, baseDir string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expanding ~: %w", err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	if baseDir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return path, nil
+}
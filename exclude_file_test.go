@@ -0,0 +1,136 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadExcludesFromFileBasics(t *testing.T) {
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, "base.searchignore"), ""+
+		"# comment\n"+
+		"\n"+
+		"literal: loads data from\n"+
+		"pattern: *_test.go\n"+
+		"dir: testdata\n"+
+		"sentinel: CACHEDIR.TAG:Signature: 8a477f597d28d172\n",
+	)
+
+	ef, err := LoadExcludesFromFile(filepath.Join(dir, "base.searchignore"))
+	if err != nil {
+		t.Fatalf("LoadExcludesFromFile: %v", err)
+	}
+
+	wantDir := filepath.Join(dir, "testdata")
+	if len(ef.Directories) != 1 || ef.Directories[0] != wantDir {
+		t.Errorf("Directories = %v, want [%s] (resolved relative to the file's own directory)", ef.Directories, wantDir)
+	}
+	if len(ef.FilePatterns) != 1 || ef.FilePatterns[0] != "*_test.go" {
+		t.Errorf("FilePatterns = %v, want [*_test.go]", ef.FilePatterns)
+	}
+	if len(ef.ExcludeLiterals) != 1 || ef.ExcludeLiterals[0] != "loads data from" {
+		t.Errorf("ExcludeLiterals = %v, want [loads data from]", ef.ExcludeLiterals)
+	}
+	if len(ef.ExcludeIfPresent) != 1 || ef.ExcludeIfPresent[0] != "CACHEDIR.TAG:Signature: 8a477f597d28d172" {
+		t.Errorf("ExcludeIfPresent = %v, want the sentinel with its content prefix intact", ef.ExcludeIfPresent)
+	}
+}
+
+func TestLoadExcludesFromFilePreservesLiteralPadding(t *testing.T) {
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, "base.searchignore"), ""+
+		"literal: using proc \n"+
+		"literal:  using proc  \n"+
+		"literal:  data into\n",
+	)
+
+	ef, err := LoadExcludesFromFile(filepath.Join(dir, "base.searchignore"))
+	if err != nil {
+		t.Fatalf("LoadExcludesFromFile: %v", err)
+	}
+	want := []string{"using proc ", " using proc  ", " data into"}
+	if len(ef.ExcludeLiterals) != len(want) {
+		t.Fatalf("ExcludeLiterals = %q, want %q", ef.ExcludeLiterals, want)
+	}
+	for i, literal := range ef.ExcludeLiterals {
+		if literal != want[i] {
+			t.Errorf("ExcludeLiterals[%d] = %q, want %q (only the separator space after the colon should be stripped)", i, literal, want[i])
+		}
+	}
+}
+
+func TestLoadExcludesFromFileTildeExpansion(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.Mkdir(filepath.Join(home, "proj"), 0o755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+	mustWriteFile(t, filepath.Join(home, "proj", "base.searchignore"), "dir: ~/proj/testdata\n")
+
+	ef, err := LoadExcludesFromFile(filepath.Join(home, "proj", "base.searchignore"))
+	if err != nil {
+		t.Fatalf("LoadExcludesFromFile: %v", err)
+	}
+	want := filepath.Join(home, "proj", "testdata")
+	if len(ef.Directories) != 1 || ef.Directories[0] != want {
+		t.Errorf("Directories = %v, want [%s]", ef.Directories, want)
+	}
+}
+
+func TestLoadExcludesFromFileInclude(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+	mustWriteFile(t, filepath.Join(sub, "other.txt"), "literal: in a data set\ndir: nested\n")
+	mustWriteFile(t, filepath.Join(dir, "base.searchignore"), "include: sub/other.txt\npattern: *.md\n")
+
+	ef, err := LoadExcludesFromFile(filepath.Join(dir, "base.searchignore"))
+	if err != nil {
+		t.Fatalf("LoadExcludesFromFile: %v", err)
+	}
+	if len(ef.ExcludeLiterals) != 1 || ef.ExcludeLiterals[0] != "in a data set" {
+		t.Errorf("ExcludeLiterals = %v, want literal from the included file", ef.ExcludeLiterals)
+	}
+	wantDir := filepath.Join(sub, "nested")
+	if len(ef.Directories) != 1 || ef.Directories[0] != wantDir {
+		t.Errorf("Directories = %v, want [%s] (resolved relative to the included file's directory)", ef.Directories, wantDir)
+	}
+	if len(ef.FilePatterns) != 1 || ef.FilePatterns[0] != "*.md" {
+		t.Errorf("FilePatterns = %v, want [*.md]", ef.FilePatterns)
+	}
+}
+
+func TestStepBoundariesSearchignorePreservesPaddedLiterals(t *testing.T) {
+	ef, err := LoadExcludesFromFile(filepath.Join("testdata", "step_boundaries.searchignore"))
+	if err != nil {
+		t.Fatalf("LoadExcludesFromFile: %v", err)
+	}
+	padded := map[string]bool{" using proc ": false, " proc casutil utility ": false}
+	for _, literal := range ef.ExcludeLiterals {
+		if _, ok := padded[literal]; ok {
+			padded[literal] = true
+		}
+	}
+	for literal, found := range padded {
+		if !found {
+			t.Errorf("expected %q to round-trip with its original padding intact, got %q", literal, ef.ExcludeLiterals)
+		}
+	}
+}
+
+func TestExclusionListExcludeFilesMerge(t *testing.T) {
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, "base.searchignore"), "pattern: *.generated.go\n")
+
+	list := ExclusionList{ExcludeFiles: []string{filepath.Join(dir, "base.searchignore")}}
+	m, err := list.buildMatcher(dir)
+	if err != nil {
+		t.Fatalf("buildMatcher: %v", err)
+	}
+	if !m.Matches("foo.generated.go", false) {
+		t.Error("expected pattern from ExcludeFiles to be merged into the matcher")
+	}
+}
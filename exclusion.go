@@ -0,0 +1,217 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExclusionList defines criteria for excluding file patterns, file extensions
+// and directories during the search. FilePatterns and Extensions are
+// gitignore-style patterns evaluated against a path's basename unless they
+// contain a "/", in which case they're anchored to the project root;
+// Directories are absolute directory paths, also relative-ized to each
+// project root before matching. See PatternMatcher for the full semantics.
+type ExclusionList struct {
+	FilePatterns []string
+	Extensions   []string
+	Directories  []string
+
+	// ExcludeIfPresent lists sentinel files whose presence inside a
+	// directory causes that directory to be skipped entirely, mirroring
+	// restic's --exclude-if-present (e.g. "CACHEDIR.TAG"). An entry may
+	// instead be "name:content-prefix" (for instance
+	// "CACHEDIR.TAG:Signature: 8a477f597d28d172"), in which case the file
+	// must both exist and start with those bytes -- this is how restic
+	// tells a real cache directory from a coincidentally named file.
+	ExcludeIfPresent []string
+
+	// ExcludeFiles lists .searchignore-style files (see
+	// LoadExcludesFromFile) whose pattern/dir/sentinel directives are
+	// merged into FilePatterns, Directories, and ExcludeIfPresent
+	// respectively. Any literal: directives they contain are collected
+	// too -- see ExcludeLiteralsFromFiles -- since exclude literals govern
+	// content matching rather than file/directory exclusion and so have
+	// nowhere to live on ExclusionList itself.
+	ExcludeFiles []string
+}
+
+// merged returns e with the path-based directives of every file in
+// ExcludeFiles loaded and appended to FilePatterns, Directories, and
+// ExcludeIfPresent, plus the literal: directives collected separately
+// since ExclusionList has no field for them.
+func (e ExclusionList) merged() (ExclusionList, []string, error) {
+	if len(e.ExcludeFiles) == 0 {
+		return e, nil, nil
+	}
+	out := e
+	out.ExcludeFiles = nil
+	var literals []string
+	for _, path := range e.ExcludeFiles {
+		ef, err := LoadExcludesFromFile(path)
+		if err != nil {
+			return ExclusionList{}, nil, fmt.Errorf("loading exclude file %s: %w", path, err)
+		}
+		out.FilePatterns = append(out.FilePatterns, ef.FilePatterns...)
+		out.Directories = append(out.Directories, ef.Directories...)
+		out.ExcludeIfPresent = append(out.ExcludeIfPresent, ef.ExcludeIfPresent...)
+		literals = append(literals, ef.ExcludeLiterals...)
+	}
+	return out, literals, nil
+}
+
+// ExcludeLiteralsFromFiles loads every file in ExcludeFiles and returns the
+// exclude literals their literal: directives declare, so a Searcher can
+// fold them into its own ExcludeLiterals alongside whatever FilePatterns,
+// Directories, and ExcludeIfPresent merging already does.
+func (e ExclusionList) ExcludeLiteralsFromFiles() ([]string, error) {
+	_, literals, err := e.merged()
+	return literals, err
+}
+
+// ExclusionReason records why a path was excluded from a search.
+type ExclusionReason struct {
+	Path   string
+	Reason string
+}
+
+// buildMatcher compiles a PatternMatcher for projectRoot from the
+// exclusion list's directories, file patterns, and extensions. Directories
+// outside projectRoot are silently skipped since they can never match a
+// path under it.
+func (e ExclusionList) buildMatcher(projectRoot string) (*PatternMatcher, error) {
+	e, _, err := e.merged()
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	for _, dir := range e.Directories {
+		rel, err := filepath.Rel(projectRoot, dir)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		patterns = append(patterns, "/"+filepath.ToSlash(rel)+"/")
+	}
+	patterns = append(patterns, e.FilePatterns...)
+	patterns = append(patterns, extensionPatterns(e.Extensions)...)
+	return NewPatternMatcher(patterns)
+}
+
+// extensionPatterns translates plain extensions (".txt") into "**/*.txt"
+// glob patterns so they flow through the same PatternMatcher as everything
+// else; entries that already look like a pattern are passed through as-is.
+func extensionPatterns(extensions []string) []string {
+	patterns := make([]string, 0, len(extensions))
+	for _, ext := range extensions {
+		if strings.ContainsAny(ext, "*?") {
+			patterns = append(patterns, ext)
+			continue
+		}
+		patterns = append(patterns, "**/*"+ext)
+	}
+	return patterns
+}
+
+// NewExclusionFilter builds a SelectFilter from an ExclusionList, compiling
+// one PatternMatcher per project root so patterns stay relative to the root
+// they were defined against. report, if non-nil, is called with the path and
+// reason for every entry the filter excludes.
+func NewExclusionFilter(exclusionList ExclusionList, projectRoots []string, report func(path, reason string)) (func(path string, d fs.DirEntry) bool, error) {
+	merged, _, err := exclusionList.merged()
+	if err != nil {
+		return nil, err
+	}
+
+	matchers := make(map[string]*PatternMatcher, len(projectRoots))
+	for _, root := range projectRoots {
+		m, err := merged.buildMatcher(root)
+		if err != nil {
+			return nil, fmt.Errorf("building exclusion matcher for %s: %w", root, err)
+		}
+		matchers[root] = m
+	}
+	sentinels := parseSentinels(merged.ExcludeIfPresent)
+
+	return func(path string, d fs.DirEntry) bool {
+		for _, root := range projectRoots {
+			rel, err := filepath.Rel(root, path)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			if matchers[root].Matches(rel, d.IsDir()) {
+				if report != nil {
+					report(path, fmt.Sprintf("matched exclude pattern for %s", filepath.ToSlash(rel)))
+				}
+				return false
+			}
+			break
+		}
+
+		if d.IsDir() {
+			if sent, present := sentinelIn(path, sentinels); present {
+				if report != nil {
+					report(path, fmt.Sprintf("sentinel file %s present", sent))
+				}
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// sentinel is a single --exclude-if-present entry: a filename, optionally
+// paired with a content prefix the file must start with.
+type sentinel struct {
+	name   string
+	prefix []byte
+}
+
+func parseSentinels(raw []string) []sentinel {
+	sentinels := make([]sentinel, 0, len(raw))
+	for _, r := range raw {
+		name, prefix, hasPrefix := strings.Cut(r, ":")
+		s := sentinel{name: name}
+		if hasPrefix {
+			s.prefix = []byte(prefix)
+		}
+		sentinels = append(sentinels, s)
+	}
+	return sentinels
+}
+
+// sentinelIn reports whether any sentinel is present in dir, returning its
+// name for the exclusion reason.
+func sentinelIn(dir string, sentinels []sentinel) (string, bool) {
+	for _, s := range sentinels {
+		if sentinelPresent(dir, s) {
+			return s.name, true
+		}
+	}
+	return "", false
+}
+
+func sentinelPresent(dir string, s sentinel) bool {
+	path := filepath.Join(dir, s.name)
+	if len(s.prefix) == 0 {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buf := make([]byte, len(s.prefix))
+	n, err := io.ReadFull(f, buf)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(buf[:n], s.prefix)
+}
@@ -0,0 +1,80 @@
+package codegen
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExclusionFilterSentinelSkipsDirectory(t *testing.T) {
+	root := t.TempDir()
+	genDir := filepath.Join(root, "generated")
+	if err := os.Mkdir(genDir, 0o755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+	mustWriteFile(t, filepath.Join(genDir, "CACHEDIR.TAG"), "Signature: 8a477f597d28d172\n")
+
+	var reasons []string
+	filter, err := NewExclusionFilter(
+		ExclusionList{ExcludeIfPresent: []string{"CACHEDIR.TAG"}},
+		[]string{root},
+		func(path, reason string) { reasons = append(reasons, reason) },
+	)
+	if err != nil {
+		t.Fatalf("NewExclusionFilter: %v", err)
+	}
+
+	d := dirEntry(t, genDir)
+	if filter(genDir, d) {
+		t.Error("expected directory containing CACHEDIR.TAG to be excluded")
+	}
+	if len(reasons) != 1 {
+		t.Fatalf("expected one exclusion reason, got %v", reasons)
+	}
+}
+
+func TestExclusionFilterSentinelContentPrefix(t *testing.T) {
+	root := t.TempDir()
+	realCache := filepath.Join(root, "real-cache")
+	fakeCache := filepath.Join(root, "fake-cache")
+	for _, d := range []string{realCache, fakeCache} {
+		if err := os.Mkdir(d, 0o755); err != nil {
+			t.Fatalf("Mkdir: %v", err)
+		}
+	}
+	mustWriteFile(t, filepath.Join(realCache, "CACHEDIR.TAG"), "Signature: 8a477f597d28d172\n")
+	mustWriteFile(t, filepath.Join(fakeCache, "CACHEDIR.TAG"), "not a real cache tag\n")
+
+	filter, err := NewExclusionFilter(
+		ExclusionList{ExcludeIfPresent: []string{"CACHEDIR.TAG:Signature: 8a477f597d28d172"}},
+		[]string{root},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("NewExclusionFilter: %v", err)
+	}
+
+	if filter(realCache, dirEntry(t, realCache)) {
+		t.Error("expected real-cache (matching content prefix) to be excluded")
+	}
+	if !filter(fakeCache, dirEntry(t, fakeCache)) {
+		t.Error("expected fake-cache (mismatched content prefix) to be scanned")
+	}
+}
+
+func dirEntry(t *testing.T, path string) fs.DirEntry {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	base := filepath.Base(path)
+	for _, e := range entries {
+		if e.Name() == base {
+			return e
+		}
+	}
+	t.Fatalf("entry %s not found", path)
+	return nil
+}
@@ -0,0 +1,145 @@
+package codegen
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pattern is a single compiled gitignore/dockerignore-style pattern.
+type pattern struct {
+	raw       string
+	regex     *regexp.Regexp
+	dirOnly   bool
+	exclusion bool
+}
+
+// PatternMatcher evaluates paths against an ordered list of gitignore-style
+// patterns, modeled on moby/patternmatcher and the gitignore pattern format.
+//
+// Patterns are matched against the path relative to a project root, in the
+// order they were supplied; the last pattern that matches a given path
+// decides whether that path is excluded. "**" matches any number of path
+// segments, "*" matches within a single segment, "?" matches a single
+// non-separator rune, a trailing "/" restricts the pattern to directories,
+// and a leading "!" negates the pattern, re-including a path an earlier
+// pattern excluded. A pattern with no "/" matches at any depth (it's
+// implicitly "**/pattern"), same as gitignore; a leading "/" anchors it to
+// the root instead, restricting it to a top-level match only.
+//
+// Because PatternMatcher only ever sees the paths its caller hands it, a
+// negated pattern cannot resurrect a file under a directory that an
+// earlier, non-negated pattern already excluded and that the walker
+// therefore pruned with filepath.SkipDir before ever reaching the child --
+// the walker never visits it to ask. This mirrors git and Docker's
+// documented behavior: it is not possible to re-include a file if a parent
+// directory of that file is excluded.
+type PatternMatcher struct {
+	patterns []*pattern
+}
+
+// NewPatternMatcher compiles patterns in the order given. Patterns use
+// forward slashes regardless of OS.
+func NewPatternMatcher(patterns []string) (*PatternMatcher, error) {
+	pm := &PatternMatcher{patterns: make([]*pattern, 0, len(patterns))}
+	for _, raw := range patterns {
+		p, err := compilePattern(raw)
+		if err != nil {
+			return nil, fmt.Errorf("compiling pattern %q: %w", raw, err)
+		}
+		pm.patterns = append(pm.patterns, p)
+	}
+	return pm, nil
+}
+
+func compilePattern(raw string) (*pattern, error) {
+	p := &pattern{raw: raw, exclusion: true}
+
+	clean := raw
+	if strings.HasPrefix(clean, "!") {
+		p.exclusion = false
+		clean = clean[1:]
+	}
+	if strings.HasSuffix(clean, "/") {
+		p.dirOnly = true
+		clean = strings.TrimSuffix(clean, "/")
+	}
+	rootAnchored := strings.HasPrefix(clean, "/")
+	clean = strings.TrimPrefix(clean, "/")
+
+	// A pattern with no separator matches at any depth, same as gitignore
+	// -- unless it was anchored to the root with a leading "/", in which
+	// case it only matches at the top level.
+	if !rootAnchored && !strings.Contains(clean, "/") {
+		clean = "**/" + clean
+	}
+
+	expr, err := translateToRegexp(clean)
+	if err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	p.regex = re
+	return p, nil
+}
+
+// translateToRegexp turns a gitignore-style glob into an anchored regexp:
+// "**/" becomes "(?:[^/]+/)*" so it only ever consumes whole path segments,
+// a bare trailing "**" becomes ".*" since there's nothing after it for a
+// partial segment to fuse with, "*" becomes "[^/]*", and "?" becomes
+// "[^/]".
+func translateToRegexp(glob string) (string, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				if i+2 < len(runes) && runes[i+2] == '/' {
+					// "**/" matches zero or more whole path segments, not a
+					// partial one -- "(?:[^/]+/)*" can't stop partway
+					// through a segment the way ".*" could, so "a/**/b"
+					// can't match "a/xb".
+					b.WriteString("(?:[^/]+/)*")
+					i += 2 // swallow the second "*" and the following "/"
+				} else {
+					b.WriteString(".*")
+					i++ // swallow the second "*"
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '.', '+', '(', ')', '|', '^', '$', '[', ']', '{', '}', '\\':
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	b.WriteString("$")
+	return b.String(), nil
+}
+
+// Matches reports whether path, relative to the project root and using
+// forward slashes, is excluded by this matcher. isDir indicates whether
+// path itself names a directory, since directory-only patterns only apply
+// to directories.
+func (pm *PatternMatcher) Matches(path string, isDir bool) bool {
+	path = filepath.ToSlash(path)
+	excluded := false
+	for _, p := range pm.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.regex.MatchString(path) {
+			excluded = p.exclusion
+		}
+	}
+	return excluded
+}
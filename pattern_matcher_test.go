@@ -0,0 +1,106 @@
+package codegen
+
+import "testing"
+
+func TestPatternMatcherNegation(t *testing.T) {
+	pm, err := NewPatternMatcher([]string{"services/**", "!services/codegen/**"})
+	if err != nil {
+		t.Fatalf("NewPatternMatcher: %v", err)
+	}
+
+	cases := []struct {
+		path string
+		want bool
+	}{
+		{"services/codetoflow/handler.go", true},
+		{"services/codegen/handler.go", false},
+		{"services/codegen/testdata/fixture.txt", false},
+		{"templates/main.go", false},
+	}
+	for _, c := range cases {
+		if got := pm.Matches(c.path, false); got != c.want {
+			t.Errorf("Matches(%q) = %v, want %v", c.path, got, c.want)
+		}
+	}
+}
+
+func TestPatternMatcherRootAnchored(t *testing.T) {
+	pm, err := NewPatternMatcher([]string{"/config.txt"})
+	if err != nil {
+		t.Fatalf("NewPatternMatcher: %v", err)
+	}
+	if !pm.Matches("config.txt", false) {
+		t.Error("expected /config.txt to match the top-level config.txt")
+	}
+	if pm.Matches("dir/config.txt", false) {
+		t.Error("expected /config.txt to be anchored to the root, not dir/config.txt")
+	}
+}
+
+func TestPatternMatcherDoubleStar(t *testing.T) {
+	pm, err := NewPatternMatcher([]string{"**/*.txt"})
+	if err != nil {
+		t.Fatalf("NewPatternMatcher: %v", err)
+	}
+	if !pm.Matches("a/b/c/report.txt", false) {
+		t.Error("expected a/b/c/report.txt to match **/*.txt")
+	}
+	if !pm.Matches("report.txt", false) {
+		t.Error("expected report.txt at root to match **/*.txt")
+	}
+	if pm.Matches("report.md", false) {
+		t.Error("did not expect report.md to match **/*.txt")
+	}
+}
+
+func TestPatternMatcherInteriorDoubleStarMatchesWholeSegmentsOnly(t *testing.T) {
+	pm, err := NewPatternMatcher([]string{"a/**/b"})
+	if err != nil {
+		t.Fatalf("NewPatternMatcher: %v", err)
+	}
+	if !pm.Matches("a/b", false) {
+		t.Error("expected a/**/b to match a/b (** matching zero segments)")
+	}
+	if !pm.Matches("a/x/b", false) {
+		t.Error("expected a/**/b to match a/x/b")
+	}
+	if !pm.Matches("a/x/y/b", false) {
+		t.Error("expected a/**/b to match a/x/y/b")
+	}
+	if pm.Matches("a/xb", false) {
+		t.Error("expected a/**/b not to match a/xb: ** must match whole segments, not a partial one")
+	}
+}
+
+func TestPatternMatcherDirOnly(t *testing.T) {
+	pm, err := NewPatternMatcher([]string{"build/"})
+	if err != nil {
+		t.Fatalf("NewPatternMatcher: %v", err)
+	}
+	if !pm.Matches("build", true) {
+		t.Error("expected directory build to be excluded")
+	}
+	if pm.Matches("build", false) {
+		t.Error("dirOnly pattern should not match a file named build")
+	}
+}
+
+// TestPatternMatcherParentPruneIsNotPatternMatcherJob documents that
+// PatternMatcher alone cannot re-include a file under an excluded parent:
+// once a walker skips the parent directory, no child path is ever offered
+// to Matches for it to negate. Matches itself never checks a path's
+// parents, so the child path passed directly here is not excluded.
+func TestPatternMatcherParentPruneIsNotPatternMatcherJob(t *testing.T) {
+	pm, err := NewPatternMatcher([]string{"build/", "!build/keep.txt"})
+	if err != nil {
+		t.Fatalf("NewPatternMatcher: %v", err)
+	}
+	// Asked directly, the child is not excluded ("build/" only matches the
+	// directory path "build", and the negation matches the child)...
+	if pm.Matches("build/keep.txt", false) {
+		t.Error("expected build/keep.txt not to be excluded when asked directly")
+	}
+	// ...but a walker whose SelectFilter rejects "build" first returns
+	// filepath.SkipDir and never asks about "build/keep.txt" at all, so the
+	// file is excluded in practice. No test here walks such a tree.
+}
@@ -0,0 +1,302 @@
+package codegen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultFDConcurrency caps concurrently open files when Searcher.Concurrency
+// is left unset, to avoid "too many open files" on Windows when scanning
+// across large project roots.
+const defaultFDConcurrency = 128
+
+// Searcher walks a set of project roots looking for string literals. It
+// started life reachable only from a test; SelectFilter and Error now make
+// it something other tools in this repo can call directly, composing their
+// own inclusion rules instead of being stuck with a hardcoded ExclusionList.
+type Searcher struct {
+	StringLiterals  []string
+	ExcludeLiterals []string
+
+	// SelectFilter decides whether an entry is scanned at all, analogous to
+	// restic's Archiver.SelectFilter. It's called for every directory and
+	// file the walk visits; returning false for a directory prunes it with
+	// filepath.SkipDir, returning false for a file skips scanning it. A nil
+	// SelectFilter scans everything.
+	SelectFilter func(path string, d fs.DirEntry) bool
+
+	// Error is called for walk and file-read errors. For a walk error
+	// (e.g. a permission problem filepath.WalkDir hits while traversing a
+	// directory) returning a non-nil error stops the walk, skipping any
+	// remaining project roots, and Search returns it without writing a
+	// report, matching prior behavior. For a file-read error, Error is
+	// still called for logging, but since file scanning now runs
+	// concurrently with the walk, its return value can no longer abort an
+	// in-flight walk -- the two are already decoupled by the time the
+	// error surfaces. A nil Error logs to stderr and continues in both
+	// cases.
+	Error func(path string, err error) error
+
+	// Concurrency caps the number of files open at once across the worker
+	// pool. Zero means defaultFDConcurrency (128).
+	Concurrency int
+
+	excludedItems []ExclusionReason
+}
+
+// NewSearcher returns a Searcher configured to scan for stringLiterals,
+// ignoring lines that also contain one of excludeLiterals or one of the
+// literal: directives in exclusionList.ExcludeFiles, with a SelectFilter
+// built from exclusionList so existing callers see unchanged behavior.
+// Callers that want to compose their own filtering (a gitignore filter
+// stacked with a size-limit or modtime filter, say) can instead construct a
+// Searcher directly and set SelectFilter themselves.
+func NewSearcher(stringLiterals, excludeLiterals []string, exclusionList ExclusionList, projectRoots []string) (*Searcher, error) {
+	// Merge exclusionList's ExcludeFiles once and reuse the result for both
+	// the exclude literals and the SelectFilter, rather than loading and
+	// parsing every file in ExcludeFiles twice over.
+	merged, fileLiterals, err := exclusionList.merged()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Searcher{
+		StringLiterals:  stringLiterals,
+		ExcludeLiterals: append(append([]string{}, excludeLiterals...), fileLiterals...),
+	}
+	filter, err := NewExclusionFilter(merged, projectRoots, s.recordExclusion)
+	if err != nil {
+		return nil, err
+	}
+	s.SelectFilter = filter
+	return s, nil
+}
+
+// recordExclusion is only ever invoked synchronously from the walker
+// goroutine (as the report callback SelectFilter closures built by
+// NewExclusionFilter call), so it needs no locking of its own.
+func (s *Searcher) recordExclusion(path, reason string) {
+	s.excludedItems = append(s.excludedItems, ExclusionReason{Path: path, Reason: reason})
+}
+
+// literalMatch is one string-literal hit, formatted for the report but kept
+// alongside its line number so matches can be sorted before being written.
+type literalMatch struct {
+	line int
+	text string
+}
+
+// fileScanResult is what a scan worker hands back to the collector for one
+// candidate file.
+type fileScanResult struct {
+	path    string
+	matches []literalMatch
+	err     error
+}
+
+// Search runs a three-stage pipeline over projectRoots -- a walker emits
+// candidate file paths past SelectFilter, a pool of runtime.NumCPU()
+// workers scans each for string literals, and a collector assembles the
+// results -- then writes excluded paths and string literal matches to
+// outputFile.
+func (s *Searcher) Search(projectRoots []string, outputFile string) error {
+	output, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %w", err)
+	}
+	defer output.Close()
+
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultFDConcurrency
+	}
+	fdSem := make(chan struct{}, concurrency)
+
+	paths := make(chan string)
+	results := make(chan fileScanResult)
+
+	var walkErr error
+	var walkWG sync.WaitGroup
+	walkWG.Add(1)
+	go func() {
+		defer walkWG.Done()
+		walkErr = s.walk(projectRoots, paths)
+	}()
+
+	go s.scan(paths, results, fdSem)
+
+	fileMatches := make(map[string][]literalMatch)
+	for res := range results {
+		if res.err != nil {
+			s.handleError(res.path, res.err)
+			continue
+		}
+		if len(res.matches) > 0 {
+			fileMatches[res.path] = res.matches
+		}
+	}
+
+	walkWG.Wait()
+	if walkErr != nil {
+		return walkErr
+	}
+
+	s.writeReport(output, fileMatches)
+	return nil
+}
+
+// walk is the pipeline's single walker stage: it emits every candidate file
+// path that survives SelectFilter onto paths, then closes it.
+func (s *Searcher) walk(projectRoots []string, paths chan<- string) error {
+	defer close(paths)
+	for _, projectRoot := range projectRoots {
+		err := filepath.WalkDir(projectRoot, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return s.handleError(path, err)
+			}
+
+			if s.SelectFilter != nil && !s.SelectFilter(path, d) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			paths <- path
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error during filepath.WalkDir: %w", err)
+		}
+	}
+	return nil
+}
+
+// scan is the pipeline's worker stage: runtime.NumCPU() goroutines pull
+// paths, each gated by fdSem while its file is open, and push results until
+// paths is drained, then close results.
+func (s *Searcher) scan(paths <-chan string, results chan<- fileScanResult, fdSem chan struct{}) {
+	var wg sync.WaitGroup
+	workers := runtime.NumCPU()
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				fdSem <- struct{}{}
+				matches, err := processFile(path, s.StringLiterals, s.ExcludeLiterals)
+				<-fdSem
+				results <- fileScanResult{path: path, matches: matches, err: err}
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+}
+
+// handleError reports a walk or file-read error via Error, falling back to
+// logging-and-continuing when Error is nil.
+func (s *Searcher) handleError(path string, err error) error {
+	if s.Error != nil {
+		return s.Error(path, err)
+	}
+	fmt.Fprintf(os.Stderr, "Error accessing path: %s, error: %v\n", path, err)
+	return nil
+}
+
+func (s *Searcher) writeReport(output *os.File, fileMatches map[string][]literalMatch) {
+	fmt.Fprintln(output, "\nExcluded Files and Directories:")
+	for _, item := range s.excludedItems {
+		fmt.Fprintf(output, "Path: %s, Reason: %s\n", item.Path, item.Reason)
+	}
+
+	fmt.Fprintln(output, "\nString Literal Matches:")
+
+	var files []string
+	for file := range fileMatches {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	// Write the matches group by file, each file's matches by line number.
+	for _, file := range files {
+		fmt.Fprintf(output, "File: %s\n", file)
+		matches := fileMatches[file]
+		sort.Slice(matches, func(i, j int) bool { return matches[i].line < matches[j].line })
+		for _, match := range matches {
+			fmt.Fprintln(output, match.text)
+		}
+	}
+
+	// Add Summary
+	fmt.Fprintln(output, "\nSummary:")
+	for _, file := range files {
+		fmt.Fprintf(output, "File: %s, Matches Found: %d\n", file, len(fileMatches[file]))
+	}
+}
+
+// processFile reads a file, strips comments with the Tokenizer appropriate
+// for its extension, searches the stripped text for string literals, and
+// reports findings.
+func processFile(filePath string, stringLiterals []string, excludeLiterals []string) ([]literalMatch, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	stripped := TokenizerFor(filepath.Ext(filePath)).StripNonCode(file)
+
+	reader := bufio.NewReader(stripped)
+	lineNumber := 1
+	var matches []literalMatch
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				break // End of file
+			}
+			return nil, fmt.Errorf("error reading line: %w", err)
+		}
+
+		for _, literal := range stringLiterals {
+			// Create a regex for word boundary matching
+			re := regexp.MustCompile(fmt.Sprintf(`\b%s\b`, regexp.QuoteMeta(literal)))
+			matchIndexes := re.FindStringIndex(line)
+
+			if matchIndexes != nil {
+				// Check if the line contains any exclude literals
+				excludeMatch := false
+				for _, excludeLiteral := range excludeLiterals {
+					if strings.Contains(line, excludeLiteral) {
+						excludeMatch = true
+						break
+					}
+				}
+				// if not excluded, then report.
+				if !excludeMatch {
+					colNumber := matchIndexes[0]
+
+					matchStr := fmt.Sprintf("  Row: %d, Column: %d\n", lineNumber, colNumber+1)
+					matchStr = matchStr + fmt.Sprintf("  Match: %s\n", literal)
+					matchStr = matchStr + fmt.Sprintf("  Line: %s\n", line)
+					matches = append(matches, literalMatch{line: lineNumber, text: matchStr})
+				}
+			}
+
+		}
+		lineNumber++
+	}
+	return matches, nil
+}
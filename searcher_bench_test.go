@@ -0,0 +1,37 @@
+package codegen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// BenchmarkSearcherPipeline exercises the walk/scan/collect pipeline over a
+// synthetic 10k-file tree to verify the parallel redesign actually speeds
+// scanning up over a single-goroutine walk.
+func BenchmarkSearcherPipeline(b *testing.B) {
+	root := b.TempDir()
+	const fileCount = 10000
+	for i := 0; i < fileCount; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("pkg%d", i%100))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			b.Fatalf("MkdirAll: %v", err)
+		}
+		content := fmt.Sprintf("package pkg%d\n\nvar s = \"proc print\"\n", i%100)
+		path := filepath.Join(dir, fmt.Sprintf("file%d.go", i))
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			b.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	outputFile := filepath.Join(b.TempDir(), "out.txt")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := &Searcher{StringLiterals: []string{"proc "}}
+		if err := s.Search([]string{root}, outputFile); err != nil {
+			b.Fatalf("Search: %v", err)
+		}
+	}
+}
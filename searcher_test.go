@@ -0,0 +1,94 @@
+package codegen
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSearcherCustomSelectFilter(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "keep.go"), "package main\n\nvar x = \"proc print\"\n")
+	mustWriteFile(t, filepath.Join(root, "skip.go"), "package main\n\nvar y = \"proc print\"\n")
+
+	s := &Searcher{
+		StringLiterals: []string{"proc "},
+		SelectFilter: func(path string, d fs.DirEntry) bool {
+			return d.IsDir() || filepath.Base(path) != "skip.go"
+		},
+	}
+
+	outputFile := filepath.Join(root, "out.txt")
+	if err := s.Search([]string{root}, outputFile); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	report, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	got := string(report)
+	if !strings.Contains(got, "keep.go") {
+		t.Errorf("expected report to mention keep.go, got:\n%s", got)
+	}
+	if strings.Contains(got, "File: "+filepath.Join(root, "skip.go")) {
+		t.Errorf("expected skip.go to be excluded from matches, got:\n%s", got)
+	}
+}
+
+func TestNewSearcherMergesExcludeLiteralsFromFiles(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "base.searchignore"), "literal: in a data set\n")
+	mustWriteFile(t, filepath.Join(root, "doc.go"), "package main\n\nvar data = \"loads data from the upstream in a data set\"\n")
+
+	exclusionList := ExclusionList{ExcludeFiles: []string{filepath.Join(root, "base.searchignore")}}
+	searcher, err := NewSearcher([]string{"data "}, nil, exclusionList, []string{root})
+	if err != nil {
+		t.Fatalf("NewSearcher: %v", err)
+	}
+	if len(searcher.ExcludeLiterals) != 1 || searcher.ExcludeLiterals[0] != "in a data set" {
+		t.Fatalf("ExcludeLiterals = %v, want [in a data set] merged in from base.searchignore", searcher.ExcludeLiterals)
+	}
+
+	outputFile := filepath.Join(root, "out.txt")
+	if err := searcher.Search([]string{root}, outputFile); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	report, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if strings.Contains(string(report), "File: "+filepath.Join(root, "doc.go")) {
+		t.Errorf("expected the literal: directive from base.searchignore to suppress this match, got:\n%s", report)
+	}
+}
+
+func TestSearcherErrorHookAbortsWalk(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.go"), "package main\n")
+
+	var hookCalls int
+	s := &Searcher{
+		StringLiterals: []string{"package "},
+		Error: func(path string, err error) error {
+			hookCalls++
+			return err
+		},
+	}
+
+	// Searching a root that doesn't exist makes WalkDir call the walk
+	// callback with the root's Lstat error; the Error hook returns it, so
+	// assert Search surfaces it.
+	if err := s.Search([]string{filepath.Join(root, "missing")}, filepath.Join(root, "out.txt")); err == nil {
+		t.Fatal("expected Search to return an error for a missing root")
+	}
+}
+
+func mustWriteFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile(%s): %v", path, err)
+	}
+}
@@ -0,0 +1,208 @@
+package codegen
+
+import (
+	"bytes"
+	"go/scanner"
+	"go/token"
+	"io"
+)
+
+// Tokenizer strips everything from a source file that isn't "code" --
+// comments -- while leaving string literals and everything else untouched,
+// and preserving line breaks so line numbers in the stripped stream still
+// line up with the original file. This replaces the old single-line
+// commentRegex, which mangled "http://" inside string literals and couldn't
+// see multi-line /* */ blocks because bufio.Reader.ReadString('\n') only
+// ever handed it one line at a time.
+type Tokenizer interface {
+	StripNonCode(r io.Reader) io.Reader
+}
+
+// TokenizerFor returns the Tokenizer appropriate for a file with the given
+// extension (as returned by filepath.Ext, including the leading "."),
+// falling back to a permissive default that strips "//" and "/* */"
+// comments without trying to understand the language's string syntax.
+func TokenizerFor(ext string) Tokenizer {
+	switch ext {
+	case ".go":
+		return goTokenizer{}
+	case ".sas":
+		return sasTokenizer{}
+	default:
+		return defaultTokenizer{}
+	}
+}
+
+// blank replaces every non-newline byte of s with a space, so a stripped
+// comment still occupies the same number of lines it did in the original.
+func blank(s string) []byte {
+	b := []byte(s)
+	for i, c := range b {
+		if c != '\n' {
+			b[i] = ' '
+		}
+	}
+	return b
+}
+
+// goTokenizer strips comments from Go source using go/scanner, so it can't
+// mistake a string literal like "http://example.com" for the start of a
+// comment the way a regex can.
+type goTokenizer struct{}
+
+func (goTokenizer) StripNonCode(r io.Reader) io.Reader {
+	src, _ := io.ReadAll(r)
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+
+	var out bytes.Buffer
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.COMMENT {
+			continue
+		}
+		offset := fset.Position(pos).Offset
+		out.Write(src[last:offset])
+		out.Write(blank(lit))
+		last = offset + len(lit)
+	}
+	out.Write(src[last:])
+	return bytes.NewReader(out.Bytes())
+}
+
+// sasTokenizer strips SAS comments: C-style "/* ... */" blocks, which can
+// start anywhere and may span many lines, and "* ... ;" statement comments,
+// which are only a comment when they open a statement (right after a ";" or
+// at the start of the file) -- elsewhere "*" is multiplication. String
+// literals ('...' and "...", including their doubled-quote escape) are
+// copied through untouched so a literal like "proc sql" inside a string
+// isn't mistaken for code, and isn't mistaken for the end of a comment
+// either.
+type sasTokenizer struct{}
+
+func (sasTokenizer) StripNonCode(r io.Reader) io.Reader {
+	src, _ := io.ReadAll(r)
+	var out bytes.Buffer
+
+	atStatementStart := true
+	i := 0
+	for i < len(src) {
+		c := src[i]
+
+		switch {
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			start := i
+			i += 2
+			for i < len(src) && !(src[i] == '*' && i+1 < len(src) && src[i+1] == '/') {
+				i++
+			}
+			if i < len(src) {
+				i += 2
+			}
+			out.Write(blank(string(src[start:i])))
+			// A comment is not itself a statement, so whatever started the
+			// statement before it (BOF or a prior ";") still applies to
+			// whatever comes right after -- leave atStatementStart alone.
+
+		case c == '*' && atStatementStart:
+			// A raw run up to the next semicolon, whatever it contains --
+			// this is a comment, not code, so there's no need to track
+			// quotes inside it. Blanked rather than dropped so every column
+			// after it stays where it was; the closing ";" is kept, since it
+			// ends the statement.
+			start := i
+			for i < len(src) && src[i] != ';' {
+				i++
+			}
+			out.Write(blank(string(src[start:i])))
+			if i < len(src) {
+				out.WriteByte(';')
+				i++
+			}
+			atStatementStart = true
+
+		case c == '\'' || c == '"':
+			out.WriteByte(c)
+			i = copySASQuoted(src, i+1, c, &out)
+			atStatementStart = false
+
+		case c == ';':
+			out.WriteByte(c)
+			i++
+			atStatementStart = true
+
+		default:
+			out.WriteByte(c)
+			i++
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				atStatementStart = false
+			}
+		}
+	}
+	return bytes.NewReader(out.Bytes())
+}
+
+// copySASQuoted copies a SAS quoted string's body (the opening quote has
+// already been written to out) through out, treating a doubled quote as an
+// escaped literal quote character rather than the string's end, and
+// returns the index just past the closing quote.
+func copySASQuoted(src []byte, i int, quote byte, out *bytes.Buffer) int {
+	for i < len(src) {
+		if src[i] == quote {
+			if i+1 < len(src) && src[i+1] == quote {
+				out.WriteByte(quote)
+				out.WriteByte(quote)
+				i += 2
+				continue
+			}
+			out.WriteByte(quote)
+			return i + 1
+		}
+		out.WriteByte(src[i])
+		i++
+	}
+	return i
+}
+
+// defaultTokenizer is the permissive fallback for extensions with no
+// dedicated Tokenizer: it strips "//" line comments and "/* */" block
+// comments the way most C-family languages do, without any notion of that
+// language's string syntax.
+type defaultTokenizer struct{}
+
+func (defaultTokenizer) StripNonCode(r io.Reader) io.Reader {
+	src, _ := io.ReadAll(r)
+	var out bytes.Buffer
+
+	i := 0
+	for i < len(src) {
+		switch {
+		case src[i] == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case src[i] == '/' && i+1 < len(src) && src[i+1] == '*':
+			start := i
+			i += 2
+			for i < len(src) && !(src[i] == '*' && i+1 < len(src) && src[i+1] == '/') {
+				i++
+			}
+			if i < len(src) {
+				i += 2
+			}
+			out.Write(blank(string(src[start:i])))
+		default:
+			out.WriteByte(src[i])
+			i++
+		}
+	}
+	return bytes.NewReader(out.Bytes())
+}
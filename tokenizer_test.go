@@ -0,0 +1,132 @@
+package codegen
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func stripAll(t *testing.T, tok Tokenizer, src string) string {
+	t.Helper()
+	out, err := io.ReadAll(tok.StripNonCode(strings.NewReader(src)))
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	return string(out)
+}
+
+func TestGoTokenizerLeavesStringLiteralsAlone(t *testing.T) {
+	src := "package main\n\nvar url = \"http://example.com\" // see docs\n"
+	got := stripAll(t, goTokenizer{}, src)
+	if !strings.Contains(got, `"http://example.com"`) {
+		t.Errorf("expected string literal to survive stripping, got:\n%s", got)
+	}
+	if strings.Contains(got, "see docs") {
+		t.Errorf("expected line comment to be stripped, got:\n%s", got)
+	}
+	if strings.Count(got, "\n") != strings.Count(src, "\n") {
+		t.Errorf("expected line count to be preserved: got %d newlines, want %d", strings.Count(got, "\n"), strings.Count(src, "\n"))
+	}
+}
+
+func TestGoTokenizerStripsMultiLineBlockComment(t *testing.T) {
+	src := "package main\n\n/*\nproc sql was here\nfilename too\n*/\nvar x = 1\n"
+	got := stripAll(t, goTokenizer{}, src)
+	if strings.Contains(got, "proc sql") || strings.Contains(got, "filename too") {
+		t.Errorf("expected multi-line block comment to be fully stripped, got:\n%s", got)
+	}
+	if !strings.Contains(got, "var x = 1") {
+		t.Errorf("expected code after the comment to survive, got:\n%s", got)
+	}
+}
+
+func TestSASTokenizerStatementCommentOnlyAtStatementStart(t *testing.T) {
+	src := "data x; * this is a comment with a libname in it;\ny = 2 * 3;\n"
+	got := stripAll(t, sasTokenizer{}, src)
+	if strings.Contains(got, "libname") {
+		t.Errorf("expected statement comment to be stripped, got:\n%s", got)
+	}
+	if !strings.Contains(got, "y = 2 * 3;") {
+		t.Errorf("expected multiplication on the next line to survive untouched, got:\n%s", got)
+	}
+}
+
+func TestSASTokenizerBlockCommentSpansLines(t *testing.T) {
+	src := "data x;\n/* a comment\nwith proc sql inside\nspanning lines */\nrun;\n"
+	got := stripAll(t, sasTokenizer{}, src)
+	if strings.Contains(got, "proc sql") {
+		t.Errorf("expected multi-line /* */ comment to be stripped, got:\n%s", got)
+	}
+	if !strings.Contains(got, "run;") {
+		t.Errorf("expected code after the comment to survive, got:\n%s", got)
+	}
+}
+
+func TestSASTokenizerStatementCommentAfterBlockComment(t *testing.T) {
+	// A block comment isn't itself a statement, so a "* ...;" statement
+	// comment immediately following one (no real code/semicolon between
+	// them) must still be recognized as a comment.
+	src := "/* header */\n* this is a comment mentioning proc print;\ndata x;\n"
+	got := stripAll(t, sasTokenizer{}, src)
+	if strings.Contains(got, "proc print") {
+		t.Errorf("expected statement comment right after a block comment to be stripped, got:\n%s", got)
+	}
+	if !strings.Contains(got, "data x;") {
+		t.Errorf("expected code after the comments to survive, got:\n%s", got)
+	}
+}
+
+func TestSASTokenizerBlockCommentDoesNotFuseAdjacentTokens(t *testing.T) {
+	// A comment is blanked, not dropped, so a keyword right up against it on
+	// either side keeps its word boundary instead of fusing with whatever
+	// follows the comment.
+	src := "filename/* c */x;\n"
+	got := stripAll(t, sasTokenizer{}, src)
+	if strings.Contains(got, "filenamex") {
+		t.Errorf("expected the comment to leave a gap instead of fusing tokens, got:\n%s", got)
+	}
+}
+
+func TestSASTokenizerStatementCommentIsBlankedNotDropped(t *testing.T) {
+	// Dropping the comment's bytes instead of blanking them would shift
+	// every column after it, so check the stripped stream stays the same
+	// length as the source rather than shrinking.
+	src := "data x;*this is a comment;y = 1;\n"
+	got := stripAll(t, sasTokenizer{}, src)
+	if len(got) != len(src) {
+		t.Errorf("expected statement comment bytes to be blanked rather than dropped: got %d bytes, want %d\n%s", len(got), len(src), got)
+	}
+}
+
+func TestSASTokenizerLeavesQuotedLiteralsAlone(t *testing.T) {
+	src := `data x; y = "proc sql"; z = 'it''s fine'; run;` + "\n"
+	got := stripAll(t, sasTokenizer{}, src)
+	if !strings.Contains(got, `"proc sql"`) {
+		t.Errorf("expected double-quoted literal to survive, got:\n%s", got)
+	}
+	if !strings.Contains(got, `'it''s fine'`) {
+		t.Errorf("expected single-quoted literal with doubled-quote escape to survive, got:\n%s", got)
+	}
+}
+
+func TestDefaultTokenizerStripsCStyleComments(t *testing.T) {
+	src := "int x = 1; // trailing\n/* block\nspanning */\nint y = 2;\n"
+	got := stripAll(t, defaultTokenizer{}, src)
+	if strings.Contains(got, "trailing") || strings.Contains(got, "block") {
+		t.Errorf("expected comments to be stripped, got:\n%s", got)
+	}
+	if !strings.Contains(got, "int y = 2;") {
+		t.Errorf("expected code after the block comment to survive, got:\n%s", got)
+	}
+}
+
+func TestDefaultTokenizerBlockCommentDoesNotFuseAdjacentTokens(t *testing.T) {
+	// A comment is blanked, not dropped, so a keyword right up against it on
+	// either side keeps its word boundary instead of fusing with whatever
+	// follows the comment.
+	src := "filename/* c */x;\n"
+	got := stripAll(t, defaultTokenizer{}, src)
+	if strings.Contains(got, "filenamex") {
+		t.Errorf("expected the comment to leave a gap instead of fusing tokens, got:\n%s", got)
+	}
+}